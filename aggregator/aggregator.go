@@ -0,0 +1,203 @@
+// Package aggregator fans in price updates reported by multiple
+// independent sources and, once enough of them agree within a
+// configured window, combines them into a single consensus price per
+// asset. The consensus price can then be wrapped into a single Open
+// Oracle payload signed by the relay's own key, so that downstream
+// consumers see one unified feed rather than one message per vendor.
+package aggregator
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"code.vegaprotocol.io/oracles-relay/metrics"
+	"code.vegaprotocol.io/oracles-relay/openoracle"
+)
+
+// ErrQuorumNotMet is returned by Aggregate when fewer than MinQuorum
+// fresh sources have reported a price for the requested asset.
+var ErrQuorumNotMet = errors.New("quorum not met")
+
+// Strategy is the consensus method used to combine the prices reported
+// by multiple sources for a single asset.
+type Strategy string
+
+const (
+	// StrategyMedian takes the median of all fresh prices.
+	StrategyMedian Strategy = "median"
+	// StrategyTrimmedMean discards TrimFraction of the outliers on
+	// each end of the sorted price set, then averages what remains.
+	StrategyTrimmedMean Strategy = "trimmed_mean"
+	// StrategyVolumeWeightedMean weights every price by the traded
+	// volume reported alongside it.
+	StrategyVolumeWeightedMean Strategy = "volume_weighted_mean"
+	// StrategyTWAP computes a time-weighted average, weighting each
+	// source's price by how recently it was reported.
+	StrategyTWAP Strategy = "twap"
+)
+
+// AssetConfig configures how prices reported for a single asset are
+// combined into a consensus price.
+type AssetConfig struct {
+	Asset string `toml:"asset"`
+	// Strategy is the consensus method applied to this asset's prices.
+	Strategy Strategy `toml:"strategy"`
+	// MinQuorum is the minimum number of independent, fresh sources
+	// required before a consensus price is emitted for this asset.
+	MinQuorum int `toml:"min_quorum"`
+	// StalenessWindow is the maximum age a reported price may have to
+	// still count towards quorum and the consensus computation.
+	StalenessWindow time.Duration `toml:"staleness_window"`
+	// TrimFraction is the fraction, between 0 and 0.5, trimmed from
+	// each end of the sorted price set. Only used with
+	// StrategyTrimmedMean.
+	TrimFraction float64 `toml:"trim_fraction"`
+}
+
+// Config is the aggregator configuration, one entry per asset.
+type Config struct {
+	Assets []AssetConfig `toml:"asset"`
+}
+
+type entry struct {
+	price      openoracle.OraclePrice
+	receivedAt time.Time
+}
+
+// Aggregator collects the prices reported by independent sources and,
+// once a quorum of fresh enough prices is available for an asset,
+// combines them into a single consensus OraclePrice.
+type Aggregator struct {
+	mu sync.Mutex
+
+	cfg     map[string]AssetConfig
+	entries map[string]map[string]entry // asset -> source -> latest entry
+}
+
+// New builds an Aggregator from its configuration, validating every
+// asset's settings first so a misconfigured TOML file is rejected at
+// startup instead of panicking the relay the first time it tries to
+// aggregate a price.
+func New(cfg Config) (*Aggregator, error) {
+	cfgByAsset := make(map[string]AssetConfig, len(cfg.Assets))
+	for _, a := range cfg.Assets {
+		if a.MinQuorum < 1 {
+			return nil, fmt.Errorf("asset %q: min_quorum must be at least 1, got %d", a.Asset, a.MinQuorum)
+		}
+		if a.Strategy == StrategyTrimmedMean && (a.TrimFraction < 0 || a.TrimFraction >= 0.5) {
+			return nil, fmt.Errorf("asset %q: trim_fraction must be in [0, 0.5), got %v", a.Asset, a.TrimFraction)
+		}
+		cfgByAsset[a.Asset] = a
+	}
+	return &Aggregator{
+		cfg:     cfgByAsset,
+		entries: map[string]map[string]entry{},
+	}, nil
+}
+
+// Add records the latest price reported by source for its asset. A
+// later call for the same (asset, source) pair replaces the previous
+// value, and assets with no matching configuration are ignored.
+func (a *Aggregator) Add(source string, price openoracle.OraclePrice) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.cfg[price.Asset]; !ok {
+		return
+	}
+
+	if _, ok := a.entries[price.Asset]; !ok {
+		a.entries[price.Asset] = map[string]entry{}
+	}
+	a.entries[price.Asset][source] = entry{price: price, receivedAt: time.Now()}
+}
+
+// Assets returns the assets this aggregator is configured to combine.
+func (a *Aggregator) Assets() []string {
+	assets := make([]string, 0, len(a.cfg))
+	for asset := range a.cfg {
+		assets = append(assets, asset)
+	}
+	return assets
+}
+
+// Aggregate returns the consensus price for asset, provided at least
+// MinQuorum sources have reported a price within the staleness window.
+// ErrQuorumNotMet is returned otherwise.
+func (a *Aggregator) Aggregate(asset string) (*openoracle.OraclePrice, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	cfg, ok := a.cfg[asset]
+	if !ok {
+		return nil, fmt.Errorf("no aggregator configuration for asset %q", asset)
+	}
+
+	now := time.Now()
+	fresh := make([]entry, 0, len(a.entries[asset]))
+	for source, e := range a.entries[asset] {
+		age := now.Sub(e.receivedAt)
+		metrics.Staleness.WithLabelValues(asset, source).Set(age.Seconds())
+
+		if cfg.StalenessWindow > 0 && age > cfg.StalenessWindow {
+			continue
+		}
+		fresh = append(fresh, e)
+	}
+
+	if len(fresh) < cfg.MinQuorum {
+		return nil, ErrQuorumNotMet
+	}
+
+	prices := make([]*big.Int, 0, len(fresh))
+	volumes := make([]*big.Int, 0, len(fresh))
+	ages := make([]time.Duration, 0, len(fresh))
+	var latestTimestamp uint64
+	for _, e := range fresh {
+		price, ok := big.NewInt(0).SetString(e.price.Price, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid price %q reported for asset %q", e.price.Price, asset)
+		}
+		prices = append(prices, price)
+
+		volume, ok := big.NewInt(0).SetString(e.price.Volume, 10)
+		if !ok {
+			volume = big.NewInt(0)
+		}
+		volumes = append(volumes, volume)
+		ages = append(ages, now.Sub(e.receivedAt))
+
+		if e.price.Timestamp > latestTimestamp {
+			latestTimestamp = e.price.Timestamp
+		}
+	}
+
+	metrics.PriceDeviation.WithLabelValues(asset).Set(priceDeviation(prices))
+
+	var consensus *big.Int
+	var err error
+	switch cfg.Strategy {
+	case StrategyMedian:
+		consensus = median(prices)
+	case StrategyTrimmedMean:
+		consensus = trimmedMean(prices, cfg.TrimFraction)
+	case StrategyVolumeWeightedMean:
+		consensus, err = volumeWeightedMean(prices, volumes)
+	case StrategyTWAP:
+		consensus = timeWeightedAverage(prices, ages, cfg.StalenessWindow)
+	default:
+		err = fmt.Errorf("unknown aggregation strategy %q for asset %q", cfg.Strategy, asset)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &openoracle.OraclePrice{
+		Asset:     asset,
+		Price:     consensus.String(),
+		Timestamp: latestTimestamp,
+	}, nil
+}
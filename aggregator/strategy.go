@@ -0,0 +1,106 @@
+package aggregator
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// median returns the median of prices, interpolating between the two
+// middle values when there is an even number of them.
+func median(prices []*big.Int) *big.Int {
+	sorted := sortedCopy(prices)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	sum := big.NewInt(0).Add(sorted[n/2-1], sorted[n/2])
+	return sum.Div(sum, big.NewInt(2))
+}
+
+// trimmedMean discards trimFraction of the outliers on each end of the
+// sorted price set, then averages what remains.
+func trimmedMean(prices []*big.Int, trimFraction float64) *big.Int {
+	sorted := sortedCopy(prices)
+	n := len(sorted)
+	trim := int(float64(n) * trimFraction)
+	sorted = sorted[trim : n-trim]
+	if len(sorted) == 0 {
+		return median(prices)
+	}
+
+	sum := big.NewInt(0)
+	for _, p := range sorted {
+		sum.Add(sum, p)
+	}
+	return sum.Div(sum, big.NewInt(int64(len(sorted))))
+}
+
+// volumeWeightedMean weights every price by its reported volume. A
+// source reporting a zero or missing volume is given a weight of one
+// so it still contributes to the consensus.
+func volumeWeightedMean(prices, volumes []*big.Int) (*big.Int, error) {
+	totalVolume := big.NewInt(0)
+	weighted := big.NewInt(0)
+	for i, p := range prices {
+		v := volumes[i]
+		if v.Sign() == 0 {
+			v = big.NewInt(1)
+		}
+		weighted.Add(weighted, big.NewInt(0).Mul(p, v))
+		totalVolume.Add(totalVolume, v)
+	}
+	if totalVolume.Sign() == 0 {
+		return nil, fmt.Errorf("no volume reported by any source")
+	}
+	return weighted.Div(weighted, totalVolume), nil
+}
+
+// timeWeightedAverage computes a time-weighted average over window,
+// giving more weight to prices reported more recently.
+func timeWeightedAverage(prices []*big.Int, ages []time.Duration, window time.Duration) *big.Int {
+	weighted := big.NewInt(0)
+	totalWeight := big.NewInt(0)
+	for i, p := range prices {
+		weight := int64(window) - int64(ages[i])
+		if weight <= 0 {
+			weight = 1
+		}
+		w := big.NewInt(weight)
+		weighted.Add(weighted, big.NewInt(0).Mul(p, w))
+		totalWeight.Add(totalWeight, w)
+	}
+	if totalWeight.Sign() == 0 {
+		return big.NewInt(0)
+	}
+	return weighted.Div(weighted, totalWeight)
+}
+
+func sortedCopy(prices []*big.Int) []*big.Int {
+	out := make([]*big.Int, len(prices))
+	copy(out, prices)
+	sort.Slice(out, func(i, j int) bool { return out[i].Cmp(out[j]) < 0 })
+	return out
+}
+
+// priceDeviation returns the relative deviation between the highest
+// and lowest of prices, as a fraction of the lowest price -- 0 when
+// every source agrees exactly, or when there are fewer than two
+// prices to compare.
+func priceDeviation(prices []*big.Int) float64 {
+	if len(prices) < 2 {
+		return 0
+	}
+
+	sorted := sortedCopy(prices)
+	low, high := sorted[0], sorted[len(sorted)-1]
+	if low.Sign() == 0 {
+		return 0
+	}
+
+	spread := big.NewInt(0).Sub(high, low)
+	ratio := new(big.Float).Quo(new(big.Float).SetInt(spread), new(big.Float).SetInt(low))
+	f, _ := ratio.Float64()
+	return f
+}
@@ -0,0 +1,99 @@
+package aggregator
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func bigInts(values ...int64) []*big.Int {
+	out := make([]*big.Int, len(values))
+	for i, v := range values {
+		out[i] = big.NewInt(v)
+	}
+	return out
+}
+
+func TestMedianOdd(t *testing.T) {
+	got := median(bigInts(30, 10, 20))
+	if want := big.NewInt(20); got.Cmp(want) != 0 {
+		t.Fatalf("median() = %v, want %v", got, want)
+	}
+}
+
+func TestMedianEven(t *testing.T) {
+	got := median(bigInts(10, 40, 20, 30))
+	if want := big.NewInt(25); got.Cmp(want) != 0 {
+		t.Fatalf("median() = %v, want %v", got, want)
+	}
+}
+
+func TestTrimmedMean(t *testing.T) {
+	// 10 values, trimming 0.2 off each end discards the two lowest and
+	// two highest, leaving 30..80 to average to 55.
+	prices := bigInts(100, 10, 20, 30, 40, 50, 60, 70, 80, 90)
+	got := trimmedMean(prices, 0.2)
+	if want := big.NewInt(55); got.Cmp(want) != 0 {
+		t.Fatalf("trimmedMean() = %v, want %v", got, want)
+	}
+}
+
+func TestTrimmedMeanFallsBackToMedianWhenFullyTrimmed(t *testing.T) {
+	// trimFraction of 0.5 on 4 values trims 2 off each end, leaving
+	// nothing to average, so the result should fall back to the
+	// median of the untrimmed set.
+	prices := bigInts(10, 20, 30, 40)
+	got := trimmedMean(prices, 0.5)
+	if want := median(prices); got.Cmp(want) != 0 {
+		t.Fatalf("trimmedMean() = %v, want median() = %v", got, want)
+	}
+}
+
+func TestVolumeWeightedMean(t *testing.T) {
+	prices := bigInts(10, 20)
+	volumes := bigInts(3, 1)
+	got, err := volumeWeightedMean(prices, volumes)
+	if err != nil {
+		t.Fatalf("volumeWeightedMean() error = %v", err)
+	}
+	// (10*3 + 20*1) / 4 = 12 (integer division)
+	if want := big.NewInt(12); got.Cmp(want) != 0 {
+		t.Fatalf("volumeWeightedMean() = %v, want %v", got, want)
+	}
+}
+
+func TestVolumeWeightedMeanZeroVolumeDefaultsToWeightOne(t *testing.T) {
+	prices := bigInts(10, 20)
+	volumes := bigInts(0, 0)
+	got, err := volumeWeightedMean(prices, volumes)
+	if err != nil {
+		t.Fatalf("volumeWeightedMean() error = %v", err)
+	}
+	if want := big.NewInt(15); got.Cmp(want) != 0 {
+		t.Fatalf("volumeWeightedMean() = %v, want %v", got, want)
+	}
+}
+
+func TestTimeWeightedAverage(t *testing.T) {
+	prices := bigInts(10, 20)
+	ages := []time.Duration{0, 5 * time.Second}
+	window := 10 * time.Second
+	got := timeWeightedAverage(prices, ages, window)
+	// weight(10) = 10s, weight(20) = 5s -> (10*10 + 20*5) / 15 = 13
+	if want := big.NewInt(13); got.Cmp(want) != 0 {
+		t.Fatalf("timeWeightedAverage() = %v, want %v", got, want)
+	}
+}
+
+func TestPriceDeviation(t *testing.T) {
+	got := priceDeviation(bigInts(100, 110))
+	if got <= 0 {
+		t.Fatalf("priceDeviation() = %v, want > 0", got)
+	}
+}
+
+func TestPriceDeviationRequiresTwoPrices(t *testing.T) {
+	if got := priceDeviation(bigInts(100)); got != 0 {
+		t.Fatalf("priceDeviation() = %v, want 0 for a single price", got)
+	}
+}
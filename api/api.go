@@ -0,0 +1,126 @@
+// Package api exposes the relay's price history and Prometheus
+// metrics over HTTP, so the store built up by the store package can be
+// queried by anyone wanting to replay verification, compute a TWAP
+// over an arbitrary window, or alert on missing updates.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"code.vegaprotocol.io/oracles-relay/metrics"
+	"code.vegaprotocol.io/oracles-relay/store"
+)
+
+// Config is the HTTP API configuration.
+type Config struct {
+	// Addr is the address the HTTP server listens on, e.g. ":8080".
+	Addr string `toml:"addr"`
+}
+
+// Server serves price history queries against a store, plus the
+// Prometheus /metrics endpoint.
+type Server struct {
+	store *store.Store
+	srv   *http.Server
+}
+
+// New builds a Server backed by st, listening on cfg.Addr once Run is
+// called.
+func New(cfg Config, st *store.Store) *Server {
+	s := &Server{store: st}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/prices/", s.handlePrices)
+	mux.HandleFunc("/latest/", s.handleLatest)
+	mux.Handle("/metrics", metrics.Handler())
+
+	s.srv = &http.Server{Addr: cfg.Addr, Handler: mux}
+	return s
+}
+
+// Run serves HTTP requests until ctx is cancelled.
+func (s *Server) Run(ctx context.Context) error {
+	errc := make(chan error, 1)
+	go func() { errc <- s.srv.ListenAndServe() }()
+
+	select {
+	case <-ctx.Done():
+		return s.srv.Close()
+	case err := <-errc:
+		if err != nil && err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	}
+}
+
+// handlePrices serves GET /prices/{asset}?from=&to=, returning every
+// record stored for asset in the [from, to] timestamp range. from and
+// to default to covering the entire history when omitted.
+func (s *Server) handlePrices(w http.ResponseWriter, r *http.Request) {
+	asset := strings.TrimPrefix(r.URL.Path, "/prices/")
+	if asset == "" {
+		http.Error(w, "missing asset", http.StatusBadRequest)
+		return
+	}
+
+	from, err := parseTimestamp(r.URL.Query().Get("from"), 0)
+	if err != nil {
+		http.Error(w, "invalid from: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	to, err := parseTimestamp(r.URL.Query().Get("to"), ^uint64(0))
+	if err != nil {
+		http.Error(w, "invalid to: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	records, err := s.store.Range(asset, from, to)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, records)
+}
+
+// handleLatest serves GET /latest/{asset}, returning the record with
+// the highest timestamp stored for asset.
+func (s *Server) handleLatest(w http.ResponseWriter, r *http.Request) {
+	asset := strings.TrimPrefix(r.URL.Path, "/latest/")
+	if asset == "" {
+		http.Error(w, "missing asset", http.StatusBadRequest)
+		return
+	}
+
+	rec, err := s.store.Latest(asset)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if rec == nil {
+		http.Error(w, "no price stored for "+asset, http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, rec)
+}
+
+func parseTimestamp(raw string, def uint64) (uint64, error) {
+	if raw == "" {
+		return def, nil
+	}
+	return strconv.ParseUint(raw, 10, 64)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,83 @@
+// Package aggregatorv3 is a go-ethereum binding for the read-only
+// methods of Chainlink's AggregatorV3Interface contract, generated the
+// same way abigen would from its ABI:
+//
+//	abigen --abi aggregator_v3_interface.abi \
+//	       --pkg aggregatorv3 --type AggregatorV3Interface \
+//	       --out aggregator_v3.go
+package aggregatorv3
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const aggregatorV3ABI = `[` +
+	`{"inputs":[],"name":"decimals","outputs":[{"internalType":"uint8","name":"","type":"uint8"}],"stateMutability":"view","type":"function"},` +
+	`{"inputs":[],"name":"latestRoundData","outputs":[` +
+	`{"internalType":"uint80","name":"roundId","type":"uint80"},` +
+	`{"internalType":"int256","name":"answer","type":"int256"},` +
+	`{"internalType":"uint256","name":"startedAt","type":"uint256"},` +
+	`{"internalType":"uint256","name":"updatedAt","type":"uint256"},` +
+	`{"internalType":"uint80","name":"answeredInRound","type":"uint80"}` +
+	`],"stateMutability":"view","type":"function"}` +
+	`]`
+
+// RoundData is the tuple returned by latestRoundData.
+type RoundData struct {
+	RoundID         *big.Int
+	Answer          *big.Int
+	StartedAt       *big.Int
+	UpdatedAt       *big.Int
+	AnsweredInRound *big.Int
+}
+
+// AggregatorV3Interface binds the read-only methods of a deployed
+// Chainlink AggregatorV3Interface contract.
+type AggregatorV3Interface struct {
+	contract *bind.BoundContract
+}
+
+// NewAggregatorV3Interface binds AggregatorV3Interface to the contract
+// deployed at address, reading its state through caller.
+func NewAggregatorV3Interface(address common.Address, caller bind.ContractCaller) (*AggregatorV3Interface, error) {
+	parsed, err := abi.JSON(strings.NewReader(aggregatorV3ABI))
+	if err != nil {
+		return nil, err
+	}
+
+	return &AggregatorV3Interface{
+		contract: bind.NewBoundContract(address, parsed, caller, nil, nil),
+	}, nil
+}
+
+// Decimals returns the number of decimals the contract's answer is
+// reported with.
+func (a *AggregatorV3Interface) Decimals(opts *bind.CallOpts) (uint8, error) {
+	var out []interface{}
+	if err := a.contract.Call(opts, &out, "decimals"); err != nil {
+		return 0, err
+	}
+	return out[0].(uint8), nil
+}
+
+// LatestRoundData returns the latest price round reported by the
+// aggregator.
+func (a *AggregatorV3Interface) LatestRoundData(opts *bind.CallOpts) (RoundData, error) {
+	var out []interface{}
+	if err := a.contract.Call(opts, &out, "latestRoundData"); err != nil {
+		return RoundData{}, err
+	}
+
+	return RoundData{
+		RoundID:         out[0].(*big.Int),
+		Answer:          out[1].(*big.Int),
+		StartedAt:       out[2].(*big.Int),
+		UpdatedAt:       out[3].(*big.Int),
+		AnsweredInRound: out[4].(*big.Int),
+	}, nil
+}
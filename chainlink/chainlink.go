@@ -0,0 +1,158 @@
+// Package chainlink reads prices from Chainlink AggregatorV3Interface
+// contracts on Ethereum and L2s, giving users an Ethereum-native feed
+// alongside Coinbase. The original round id is kept on the decoded
+// OraclePrice for traceability, even though it is not part of the
+// message the relay signs.
+package chainlink
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"code.vegaprotocol.io/oracles-relay/chainlink/aggregatorv3"
+	"code.vegaprotocol.io/oracles-relay/openoracle"
+	"code.vegaprotocol.io/oracles-relay/sources"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pelletier/go-toml"
+)
+
+func init() {
+	sources.Register("chainlink", func(tree *toml.Tree) (sources.Source, time.Duration, error) {
+		cfg := Config{}
+		if err := tree.Unmarshal(&cfg); err != nil {
+			return nil, 0, err
+		}
+		worker, err := New(cfg)
+		if err != nil {
+			return nil, 0, err
+		}
+		return worker, cfg.Frequency, nil
+	})
+}
+
+// priceDecimals is the fixed-point precision prices are rescaled to
+// before being bundled into an Open Oracle payload, matching the
+// precision used by the coinbase source.
+const priceDecimals = 6
+
+// FeedConfig describes a single Chainlink price feed to read from.
+type FeedConfig struct {
+	// Asset is the name this feed's price is reported under (e.g. BTC, ETH).
+	Asset string `toml:"asset"`
+	// ContractAddress is the address of the AggregatorV3Interface
+	// contract to read from.
+	ContractAddress string `toml:"contract_address"`
+	// RPCURL is the JSON-RPC endpoint used to read this feed.
+	RPCURL string `toml:"rpc_url"`
+	// Decimals is the number of decimals the contract's answer is
+	// reported with.
+	Decimals uint8 `toml:"decimals"`
+}
+
+// Config is the chainlink worker configuration, one feed per asset.
+type Config struct {
+	Feeds []FeedConfig `toml:"feed"`
+	// Frequency is how often we poll every feed for its latest round.
+	Frequency time.Duration `toml:"frequency"`
+	// Heartbeat is the maximum age a round may have before it is
+	// considered stale and skipped.
+	Heartbeat time.Duration `toml:"heartbeat"`
+}
+
+type feed struct {
+	cfg      FeedConfig
+	contract *aggregatorv3.AggregatorV3Interface
+}
+
+// Worker reads prices from a set of Chainlink aggregator contracts.
+type Worker struct {
+	cfg   Config
+	feeds []*feed
+}
+
+// New dials the RPC endpoint for every configured feed and binds its
+// aggregator contract, returning a worker ready to be pulled from.
+func New(cfg Config) (*Worker, error) {
+	w := &Worker{cfg: cfg}
+
+	for _, fc := range cfg.Feeds {
+		client, err := ethclient.Dial(fc.RPCURL)
+		if err != nil {
+			return nil, fmt.Errorf("unable to dial %v for asset %v: %w", fc.RPCURL, fc.Asset, err)
+		}
+
+		contract, err := aggregatorv3.NewAggregatorV3Interface(common.HexToAddress(fc.ContractAddress), client)
+		if err != nil {
+			return nil, fmt.Errorf("unable to bind aggregator contract for asset %v: %w", fc.Asset, err)
+		}
+
+		w.feeds = append(w.feeds, &feed{cfg: fc, contract: contract})
+	}
+
+	return w, nil
+}
+
+// Name returns the name of this worker, used to identify it as an
+// independent source when aggregating prices across workers.
+func (w *Worker) Name() string {
+	return "chainlink"
+}
+
+// PubKey returns the address expected to have signed this source's
+// payloads. Chainlink round data is read directly from chain rather
+// than signed by a vendor key, so there is none to verify against.
+func (w *Worker) PubKey() string {
+	return ""
+}
+
+// Pull reads the latest round from every configured feed, skipping
+// any round older than Heartbeat.
+func (w *Worker) Pull(ctx context.Context) ([]openoracle.OraclePrice, error) {
+	opts := &bind.CallOpts{Context: ctx}
+
+	prices := make([]openoracle.OraclePrice, 0, len(w.feeds))
+	for _, f := range w.feeds {
+		round, err := f.contract.LatestRoundData(opts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read latest round for asset %v: %w", f.cfg.Asset, err)
+		}
+
+		updatedAt := time.Unix(round.UpdatedAt.Int64(), 0)
+		if w.cfg.Heartbeat > 0 && time.Since(updatedAt) > w.cfg.Heartbeat {
+			// the round hasn't been refreshed recently enough to be
+			// trusted, skip it rather than relaying a stale price
+			continue
+		}
+
+		prices = append(prices, openoracle.OraclePrice{
+			Asset:     f.cfg.Asset,
+			Price:     rescale(round.Answer, f.cfg.Decimals).String(),
+			Timestamp: uint64(updatedAt.Unix()),
+			RoundID:   round.RoundID.String(),
+		})
+	}
+
+	return prices, nil
+}
+
+// rescale converts a price reported with decimals precision to the
+// fixed priceDecimals precision used across all sources.
+func rescale(price *big.Int, decimals uint8) *big.Int {
+	out := big.NewInt(0).Set(price)
+	switch {
+	case decimals > priceDecimals:
+		return out.Div(out, pow10(decimals-priceDecimals))
+	case decimals < priceDecimals:
+		return out.Mul(out, pow10(priceDecimals-decimals))
+	default:
+		return out
+	}
+}
+
+func pow10(n uint8) *big.Int {
+	return big.NewInt(0).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
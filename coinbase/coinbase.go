@@ -1,6 +1,7 @@
 package coinbase
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
@@ -12,7 +13,11 @@ import (
 	"path"
 	"time"
 
+	"code.vegaprotocol.io/oracles-relay/metrics"
 	"code.vegaprotocol.io/oracles-relay/openoracle"
+	"code.vegaprotocol.io/oracles-relay/sources"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pelletier/go-toml"
 )
 
 const (
@@ -21,6 +26,16 @@ const (
 	cbPubKey = "0xfCEAdAFab14d46e20144F48824d0C09B1a03F2BC"
 )
 
+func init() {
+	sources.Register("coinbase", func(tree *toml.Tree) (sources.Source, time.Duration, error) {
+		cfg := Config{}
+		if err := tree.Unmarshal(&cfg); err != nil {
+			return nil, 0, err
+		}
+		return New(cfg), cfg.Frequency, nil
+	})
+}
+
 // Config the configuration used to connect
 // with the coinbase API
 type Config struct {
@@ -43,32 +58,57 @@ func New(cfg Config) *Worker {
 	}
 }
 
-// Pull will call the coinbase oracle API
-// and return the last updates available
-func (p *Worker) Pull() ([]byte, error) {
-	t, err := p.getTime()
+// Name returns the name of this worker, used to identify it as an
+// independent source when aggregating prices across workers.
+func (p *Worker) Name() string {
+	return "coinbase"
+}
+
+// PubKey returns the address expected to have signed coinbase's
+// oracle payloads.
+func (p *Worker) PubKey() string {
+	return cbPubKey
+}
+
+// Pull will call the coinbase oracle API, verify the payload it
+// returns, and decode it into the prices it carries.
+func (p *Worker) Pull(ctx context.Context) ([]openoracle.OraclePrice, error) {
+	t, err := p.getTime(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	btes, err := p.getOracleData(t)
+	btes, err := p.getOracleData(ctx, t)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = openoracle.UnmarshalVerify(btes, cbPubKey)
+	oresp, err := openoracle.Unmarshal(btes)
 	if err != nil {
 		return nil, err
 	}
 
-	return btes, nil
+	signers, keyValues, err := openoracle.Verify(*oresp)
+	if err != nil {
+		metrics.VerificationFailures.WithLabelValues(p.Name()).Inc()
+		return nil, err
+	}
+
+	for _, signer := range signers {
+		if common.HexToAddress(signer) != common.HexToAddress(p.PubKey()) {
+			metrics.VerificationFailures.WithLabelValues(p.Name()).Inc()
+			return nil, fmt.Errorf("payload signed by unexpected address %v, expected %v", signer, p.PubKey())
+		}
+	}
+
+	return openoracle.DecodePrices(keyValues)
 }
 
 // getOracleData calls the coinbase API and return
 // the raw data from the /oracle endpoint
-func (p *Worker) getOracleData(t int64) ([]byte, error) {
+func (p *Worker) getOracleData(ctx context.Context, t int64) ([]byte, error) {
 	client := &http.Client{}
-	req, err := http.NewRequest("GET", urlJoin(baseURL, "oracle"), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", urlJoin(baseURL, "oracle"), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -96,8 +136,12 @@ func (p *Worker) getOracleData(t int64) ([]byte, error) {
 // to set a timestamp being in a range of 30 seconds
 // around the current timestamp from their API
 // using this give us close to no chance to be wrong
-func (p *Worker) getTime() (int64, error) {
-	resp, err := http.Get(urlJoin(baseURL, "time"))
+func (p *Worker) getTime(ctx context.Context) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", urlJoin(baseURL, "time"), nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return 0, err
 	}
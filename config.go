@@ -3,27 +3,60 @@ package main
 import (
 	"io/ioutil"
 
-	"code.vegaprotocol.io/oracles-relay/coinbase"
+	"code.vegaprotocol.io/oracles-relay/aggregator"
+	"code.vegaprotocol.io/oracles-relay/api"
+	"code.vegaprotocol.io/oracles-relay/gossip"
+	"code.vegaprotocol.io/oracles-relay/openoracle"
+	"code.vegaprotocol.io/oracles-relay/store"
+	"code.vegaprotocol.io/oracles-relay/vega"
 	"github.com/pelletier/go-toml"
 )
 
 type Config struct {
 	NodeAddr string `toml:"node_addr"`
-	// The coinbase config is not mandatory
-	// if nil, we do not start the worker
-	Coinbase *coinbase.Config `toml:"coinbase"`
+	// RelayKey is the hex encoded private key used by the relay to
+	// sign the consensus payloads produced by the aggregator.
+	RelayKey string `toml:"relay_key"`
+	// Signing selects the mode the relay signs its own consensus
+	// payloads with. Not mandatory; if nil, the original
+	// eth_signed_message mode is used.
+	Signing *openoracle.SigningConfig `toml:"signing"`
+	// The aggregator config is not mandatory
+	// if nil, fetched prices are printed as-is without aggregation
+	Aggregator *aggregator.Config `toml:"aggregator"`
+	// The vega config is not mandatory
+	// if nil, consensus prices are logged instead of submitted to vega
+	Vega *vega.Config `toml:"vega"`
+	// The gossip config is not mandatory
+	// if nil, consensus prices are not broadcast to other relays
+	Gossip *gossip.Config `toml:"gossip"`
+	// The store config is not mandatory
+	// if nil, verified prices are not persisted to disk
+	Store *store.Config `toml:"store"`
+	// The api config is not mandatory, and requires Store to be set
+	// if nil, the price history and metrics HTTP endpoints are not served
+	API *api.Config `toml:"api"`
 }
 
-func loadConfig(path string) (*Config, error) {
+// loadConfig reads and unmarshals path into a Config, and also
+// returns the raw parsed tree so that sources.Discover can pick out
+// whichever backend sections (coinbase, chainlink, ...) are present,
+// without Config needing a field per backend.
+func loadConfig(path string) (*Config, *toml.Tree, error) {
 	data, err := ioutil.ReadFile(path)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	tree, err := toml.LoadBytes(data)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	config := Config{}
-	if err := toml.Unmarshal(data, &config); err != nil {
-		return nil, err
+	if err := tree.Unmarshal(&config); err != nil {
+		return nil, nil, err
 	}
 
-	return &config, nil
+	return &config, tree, nil
 }
@@ -0,0 +1,180 @@
+// Package gossip fans out signed Open Oracle payloads to other relays
+// and consumers over a libp2p gossipsub topic, so a network of relays
+// can share one set of upstream pulls (Coinbase, chainlink, ...)
+// instead of every node hitting the same APIs independently.
+// Subscribers verify payloads on their own using openoracle.Verify;
+// this package only concerns itself with getting bytes onto the wire.
+package gossip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"code.vegaprotocol.io/oracles-relay/openoracle"
+	"github.com/libp2p/go-libp2p"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// topicName is the gossipsub topic consensus prices are published on.
+const topicName = "/vega/oracle/prices/1.0.0"
+
+// seenTTL bounds how long a published (asset, timestamp) pair is kept
+// in Publisher.seen for deduplication. The relay runs for the
+// lifetime of the process, so seen is swept periodically instead of
+// growing without bound.
+const seenTTL = 10 * time.Minute
+
+// Config is the gossip publication subsystem configuration.
+type Config struct {
+	// ListenAddrs are the multiaddrs this node's libp2p host listens
+	// on, e.g. "/ip4/0.0.0.0/tcp/4001".
+	ListenAddrs []string `toml:"listen_addrs"`
+	// BootstrapPeers are multiaddrs, including the peer ID, of peers
+	// to connect to on startup so this node joins the existing mesh
+	// rather than only ever talking to itself.
+	BootstrapPeers []string `toml:"bootstrap_peers"`
+	// AllowedPeers, if non-empty, restricts the topic to messages
+	// authored by these peer IDs. Left empty, messages from any peer
+	// are accepted.
+	AllowedPeers []string `toml:"allowed_peers"`
+}
+
+// Publisher broadcasts signed Open Oracle payloads on the gossipsub
+// topic, deduplicating repeat broadcasts of the same consensus price.
+type Publisher struct {
+	host  host.Host
+	ps    *pubsub.PubSub
+	topic *pubsub.Topic
+
+	mu   sync.Mutex
+	seen map[string]time.Time // "asset:timestamp" -> when it was published
+}
+
+// New starts a libp2p host per cfg, joins the gossipsub topic and
+// connects to every configured bootstrap peer.
+func New(ctx context.Context, cfg Config) (*Publisher, error) {
+	var opts []libp2p.Option
+	if len(cfg.ListenAddrs) > 0 {
+		opts = append(opts, libp2p.ListenAddrStrings(cfg.ListenAddrs...))
+	}
+
+	h, err := libp2p.New(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create libp2p host: %w", err)
+	}
+
+	ps, err := pubsub.NewGossipSub(ctx, h)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create gossipsub: %w", err)
+	}
+
+	topic, err := ps.Join(topicName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to join topic %v: %w", topicName, err)
+	}
+
+	if len(cfg.AllowedPeers) > 0 {
+		allowed := make(map[peer.ID]struct{}, len(cfg.AllowedPeers))
+		for _, s := range cfg.AllowedPeers {
+			id, err := peer.Decode(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid allowed_peers entry %q: %w", s, err)
+			}
+			allowed[id] = struct{}{}
+		}
+
+		validator := func(_ context.Context, _ peer.ID, msg *pubsub.Message) bool {
+			_, ok := allowed[msg.GetFrom()]
+			return ok
+		}
+		if err := ps.RegisterTopicValidator(topicName, validator); err != nil {
+			return nil, fmt.Errorf("unable to register topic validator: %w", err)
+		}
+	}
+
+	p := &Publisher{
+		host:  h,
+		ps:    ps,
+		topic: topic,
+		seen:  map[string]time.Time{},
+	}
+
+	for _, addr := range cfg.BootstrapPeers {
+		if err := p.connect(ctx, addr); err != nil {
+			log.Printf("unable to connect to bootstrap peer %v: %v", addr, err)
+		}
+	}
+
+	go p.sweepSeen(ctx)
+
+	return p, nil
+}
+
+// sweepSeen periodically forgets seen entries older than seenTTL,
+// until ctx is cancelled.
+func (p *Publisher) sweepSeen(ctx context.Context) {
+	t := time.NewTicker(seenTTL)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-t.C:
+			p.mu.Lock()
+			for key, at := range p.seen {
+				if now.Sub(at) > seenTTL {
+					delete(p.seen, key)
+				}
+			}
+			p.mu.Unlock()
+		}
+	}
+}
+
+func (p *Publisher) connect(ctx context.Context, addr string) error {
+	maddr, err := multiaddr.NewMultiaddr(addr)
+	if err != nil {
+		return err
+	}
+	info, err := peer.AddrInfoFromP2pAddr(maddr)
+	if err != nil {
+		return err
+	}
+	return p.host.Connect(ctx, *info)
+}
+
+// Publish broadcasts oresp on the gossipsub topic, attributed to
+// asset, unless an identical (asset, timestamp) pair was already
+// published -- guarding against rebroadcast storms when the same
+// consensus price is re-emitted on consecutive ticks.
+func (p *Publisher) Publish(ctx context.Context, asset string, oresp *openoracle.OracleResponse) error {
+	key := fmt.Sprintf("%v:%v", asset, oresp.Timestamp)
+
+	p.mu.Lock()
+	if _, ok := p.seen[key]; ok {
+		p.mu.Unlock()
+		return nil
+	}
+	p.seen[key] = time.Now()
+	p.mu.Unlock()
+
+	payload, err := json.Marshal(oresp)
+	if err != nil {
+		return fmt.Errorf("unable to marshal oracle response: %w", err)
+	}
+
+	return p.topic.Publish(ctx, payload)
+}
+
+// Close shuts down this publisher's libp2p host.
+func (p *Publisher) Close() error {
+	return p.host.Close()
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"flag"
 	"fmt"
 	"log"
@@ -9,9 +10,21 @@ import (
 	"os/signal"
 	"sync"
 	"syscall"
-	"time"
 
-	"code.vegaprotocol.io/oracles-relay/coinbase"
+	"code.vegaprotocol.io/oracles-relay/aggregator"
+	"code.vegaprotocol.io/oracles-relay/api"
+	"code.vegaprotocol.io/oracles-relay/gossip"
+	"code.vegaprotocol.io/oracles-relay/openoracle"
+	"code.vegaprotocol.io/oracles-relay/sources"
+	"code.vegaprotocol.io/oracles-relay/store"
+	"code.vegaprotocol.io/oracles-relay/vega"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+
+	// blank-imported so their init() registers them with the sources
+	// package; add a new backend by importing it here, not by adding
+	// an if to this file
+	_ "code.vegaprotocol.io/oracles-relay/chainlink"
+	_ "code.vegaprotocol.io/oracles-relay/coinbase"
 )
 
 var flags = struct {
@@ -26,7 +39,7 @@ func main() {
 	flag.Parse()
 
 	// load our configuration
-	config, err := loadConfig(flags.Config)
+	config, tree, err := loadConfig(flags.Config)
 	if err != nil {
 		log.Printf("unable to read configuration: %v", err)
 		return
@@ -34,16 +47,106 @@ func main() {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	ch := make(chan []byte, 1000)
+	ch := make(chan sources.PriceUpdate, 1000)
 
 	wg := sync.WaitGroup{}
 
-	// build the specifed workers from the config
-	if config.Coinbase != nil {
+	// discover every source backend with a matching section in the
+	// config file and run each at its own frequency, with its own
+	// backoff on error; adding a backend is a matter of registering it
+	// with the sources package, not editing this loop
+	srcs, freqs, err := sources.Discover(tree)
+	if err != nil {
+		log.Printf("unable to build sources: %v", err)
+		return
+	}
+	for name, src := range srcs {
 		wg.Add(1)
-		go startWorker(
-			ctx, coinbase.New(*config.Coinbase), config.Coinbase.Frequency, ch, &wg,
-		)
+		go sources.Run(ctx, src, freqs[name], ch, &wg)
+	}
+
+	// signMode and signDomain select the format the relay signs its
+	// own consensus payloads with; eip712 requires a domain (chain ID
+	// and verifying contract) to be configured
+	signMode := openoracle.ModeEthSignedMessage
+	var signDomain *openoracle.EIP712Domain
+	if config.Signing != nil {
+		signMode, signDomain, err = config.Signing.Resolve()
+		if err != nil {
+			log.Printf("invalid signing configuration: %v", err)
+			return
+		}
+	}
+
+	// if an aggregator is configured, consensus prices are combined
+	// from all configured sources and re-signed with the relay's own
+	// key, otherwise we just print every price pulled from a source as-is
+	var agg *aggregator.Aggregator
+	var relayKey *ecdsa.PrivateKey
+	if config.Aggregator != nil {
+		agg, err = aggregator.New(*config.Aggregator)
+		if err != nil {
+			log.Printf("invalid aggregator configuration: %v", err)
+			return
+		}
+
+		relayKey, err = ethcrypto.HexToECDSA(config.RelayKey)
+		if err != nil {
+			log.Printf("invalid relay_key: %v", err)
+			return
+		}
+	}
+
+	// if vega submission is configured, consensus prices are pushed to
+	// the configured node instead of just being logged
+	var pipeline *vega.Pipeline
+	if config.Vega != nil {
+		pipeline, err = vega.New(config.NodeAddr, *config.Vega)
+		if err != nil {
+			log.Printf("unable to start vega submission pipeline: %v", err)
+			return
+		}
+		go pipeline.Run(ctx)
+	}
+
+	// if gossip is configured, consensus prices are also broadcast to
+	// other relays over libp2p instead of only being submitted locally
+	var publisher *gossip.Publisher
+	if config.Gossip != nil {
+		publisher, err = gossip.New(ctx, *config.Gossip)
+		if err != nil {
+			log.Printf("unable to start gossip publisher: %v", err)
+			return
+		}
+		defer publisher.Close()
+	}
+
+	// if a store is configured, every consensus price signed by the
+	// relay is persisted for later replay, TWAP computation, or alerting
+	var st *store.Store
+	if config.Store != nil {
+		st, err = store.New(*config.Store)
+		if err != nil {
+			log.Printf("unable to open store: %v", err)
+			return
+		}
+		defer st.Close()
+	}
+
+	// if the api is configured, the price history and /metrics
+	// endpoints are served over HTTP; it requires a store to query
+	if config.API != nil {
+		if st == nil {
+			log.Printf("api is configured but store is not, nothing to serve")
+			return
+		}
+
+		apiServer := api.New(*config.API, st)
+		go func() {
+			if err := apiServer.Run(ctx); err != nil {
+				log.Printf("api server error: %v", err)
+			}
+		}()
 	}
 
 	// a bunch of signals to catch
@@ -57,40 +160,67 @@ func main() {
 			cancel()
 			wg.Wait()
 			return
-		case btes := <-ch:
-			// at some point we'll send the oracle data to vega
-			// for now we dump them :)
-			fmt.Printf("%v\n", string(btes))
-		}
-	}
-}
+		case msg := <-ch:
+			if agg == nil {
+				// at some point we'll send the oracle data to vega
+				// for now we dump them :)
+				fmt.Printf("%v: %+v\n", msg.Source, msg.Price)
+				continue
+			}
 
-type worker interface {
-	Pull() ([]byte, error)
-}
+			agg.Add(msg.Source, msg.Price)
 
-func startWorker(
-	ctx context.Context,
-	w worker,
-	freq time.Duration,
-	ch chan<- []byte,
-	wg *sync.WaitGroup,
-) {
-	t := time.NewTicker(freq)
-	for {
-		t.Reset(freq)
-		select {
-		case <-ctx.Done():
-			wg.Done()
-			return
-		case <-t.C:
-			// call worker
-			btes, err := w.Pull()
-			if err != nil {
-				log.Printf("error pulling data from worker: %v", err)
-				continue
+			for _, asset := range agg.Assets() {
+				price, err := agg.Aggregate(asset)
+				if err != nil {
+					if err != aggregator.ErrQuorumNotMet {
+						log.Printf("error aggregating %v: %v", asset, err)
+					}
+					continue
+				}
+
+				oresp, err := emit(price, relayKey, signMode, signDomain)
+				if err != nil {
+					log.Printf("error signing consensus price for %v: %v", asset, err)
+					continue
+				}
+
+				if publisher != nil {
+					if err := publisher.Publish(ctx, asset, oresp); err != nil {
+						log.Printf("error broadcasting consensus price for %v: %v", asset, err)
+					}
+				}
+
+				if st != nil {
+					if err := st.Put(store.Record{
+						Asset:     asset,
+						Source:    "relay",
+						Price:     price.Price,
+						Timestamp: price.Timestamp,
+						Message:   oresp.Messages[0],
+						Signature: oresp.Signatures[0],
+					}); err != nil {
+						log.Printf("error persisting consensus price for %v: %v", asset, err)
+					}
+				}
+
+				if pipeline == nil {
+					fmt.Printf("%+v\n", oresp)
+					continue
+				}
+				pipeline.Enqueue(asset, *price, oresp)
 			}
-			ch <- btes
 		}
 	}
 }
+
+// emit wraps a single consensus price into an Open Oracle payload
+// signed by the relay's own key, using mode and, when mode is
+// ModeEIP712, domain.
+func emit(price *openoracle.OraclePrice, relayKey *ecdsa.PrivateKey, mode openoracle.SignMode, domain *openoracle.EIP712Domain) (*openoracle.OracleResponse, error) {
+	oreq := openoracle.OracleRequest{
+		Timestamp: price.Timestamp,
+		Prices:    []openoracle.OraclePrice{*price},
+	}
+	return oreq.IntoOpenOracle(relayKey, mode, domain)
+}
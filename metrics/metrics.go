@@ -0,0 +1,60 @@
+// Package metrics holds the Prometheus metrics tracked across the
+// relay -- per-source pull latency and verification failures, and
+// per-asset staleness and cross-source price deviation -- so the
+// relay's health is observable beyond log.Printf.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// PullLatency tracks how long a source's Pull call took, labeled
+	// by source name.
+	PullLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "oracles_relay_pull_latency_seconds",
+		Help: "Time taken to pull prices from a source.",
+	}, []string{"source"})
+
+	// VerificationFailures counts payloads that failed signature
+	// verification, labeled by source name.
+	VerificationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "oracles_relay_verification_failures_total",
+		Help: "Number of payloads that failed signature verification, by source.",
+	}, []string{"source"})
+
+	// Staleness is the age of the most recent price reported by a
+	// source for an asset, stale or not, labeled by asset and source.
+	Staleness = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "oracles_relay_source_staleness_seconds",
+		Help: "Age of the most recent price reported by a source for an asset.",
+	}, []string{"asset", "source"})
+
+	// PriceDeviation is the maximum relative deviation between fresh
+	// sources' prices for an asset, labeled by asset.
+	PriceDeviation = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "oracles_relay_price_deviation_ratio",
+		Help: "Maximum relative deviation between fresh sources' prices for an asset.",
+	}, []string{"asset"})
+
+	// VegaSubmissions counts the outcomes of oracle data submissions
+	// made by the vega pipeline, labeled by asset and outcome
+	// (submitted, retried, rejected, duplicate, dropped).
+	VegaSubmissions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "oracles_relay_vega_submissions_total",
+		Help: "Outcomes of oracle data submissions to vega, by asset and outcome.",
+	}, []string{"asset", "outcome"})
+)
+
+func init() {
+	prometheus.MustRegister(PullLatency, VerificationFailures, Staleness, PriceDeviation, VegaSubmissions)
+}
+
+// Handler serves the aggregated metrics in the Prometheus exposition
+// format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,288 @@
+package openoracle
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SignMode selects the message format used to sign and verify an Open
+// Oracle payload.
+type SignMode int
+
+const (
+	// ModeEthSignedMessage signs keccak256(abiEncode(message)) wrapped
+	// in the standard "\x19Ethereum Signed Message:\n32" prefix. This
+	// is the format this relay has always used.
+	ModeEthSignedMessage SignMode = iota
+	// ModeEIP712 signs the message as EIP-712 typed structured data,
+	// the format many modern consumers and hardware wallets expect,
+	// letting Solidity verifiers call ecrecover directly against the
+	// typed-data digest.
+	ModeEIP712
+)
+
+// eip712Version marks a payload as signed with ModeEIP712, via the
+// OracleResponse.Version field.
+const eip712Version = "eip712"
+
+// EIP712Domain is the domain separator a ModeEIP712 payload is signed
+// and verified against, per EIP-712's
+// EIP712Domain(string name,string version,uint256 chainId,address verifyingContract).
+// The name and version are fixed to "Open Oracle" and "1".
+type EIP712Domain struct {
+	ChainID           *big.Int
+	VerifyingContract common.Address
+}
+
+// SigningConfig selects the mode the relay signs its own consensus
+// payloads with. If left unset, Resolve defaults to
+// ModeEthSignedMessage, the format this relay has always used.
+type SigningConfig struct {
+	// Mode is either "eth_signed_message" (the default) or "eip712".
+	Mode string `toml:"mode"`
+	// ChainID is the EIP-712 domain's chainId. Required when Mode is
+	// "eip712".
+	ChainID int64 `toml:"chain_id"`
+	// VerifyingContract is the EIP-712 domain's verifyingContract,
+	// hex encoded. Required when Mode is "eip712".
+	VerifyingContract string `toml:"verifying_contract"`
+}
+
+// Resolve validates c and returns the SignMode and, when Mode is
+// "eip712", the EIP712Domain it describes.
+func (c SigningConfig) Resolve() (SignMode, *EIP712Domain, error) {
+	switch c.Mode {
+	case "", "eth_signed_message":
+		return ModeEthSignedMessage, nil, nil
+	case "eip712":
+		if c.ChainID == 0 {
+			return 0, nil, fmt.Errorf("signing: chain_id is required when mode is %q", c.Mode)
+		}
+		if c.VerifyingContract == "" {
+			return 0, nil, fmt.Errorf("signing: verifying_contract is required when mode is %q", c.Mode)
+		}
+		domain := &EIP712Domain{
+			ChainID:           big.NewInt(c.ChainID),
+			VerifyingContract: common.HexToAddress(c.VerifyingContract),
+		}
+		return ModeEIP712, domain, nil
+	default:
+		return 0, nil, fmt.Errorf("signing: unknown mode %q", c.Mode)
+	}
+}
+
+// messageFields are the logical contents of a single price message,
+// shared by both signing modes; ModeEthSignedMessage signs their ABI
+// encoding, ModeEIP712 signs their EIP-712 struct hash.
+type messageFields struct {
+	kind      string
+	key       string
+	timestamp uint64
+	value     *big.Int
+}
+
+var (
+	eip712DomainTypeHash  = crypto.Keccak256Hash([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+	eip712MessageTypeHash = crypto.Keccak256Hash([]byte("Message(string kind,uint64 timestamp,string key,uint256 value)"))
+	eip712Name            = crypto.Keccak256Hash([]byte("Open Oracle"))
+	eip712DomainVersion   = crypto.Keccak256Hash([]byte("1"))
+)
+
+// signEIP712 signs fields as EIP-712 typed structured data under
+// domain.
+func signEIP712(fields messageFields, domain EIP712Domain, privKey *ecdsa.PrivateKey) ([]byte, error) {
+	digest, err := eip712Digest(fields, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := crypto.Sign(digest.Bytes(), privKey)
+	if err != nil {
+		return nil, err
+	}
+
+	signature[64] = signature[64] + 27
+
+	return signature, nil
+}
+
+// eip712Digest computes keccak256("\x19\x01" || domainSeparator || hashStruct(fields)).
+func eip712Digest(fields messageFields, domain EIP712Domain) (common.Hash, error) {
+	ds, err := eip712DomainSeparator(domain)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	sh, err := eip712StructHash(fields)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	buf := make([]byte, 0, 2+len(ds)+len(sh))
+	buf = append(buf, 0x19, 0x01)
+	buf = append(buf, ds.Bytes()...)
+	buf = append(buf, sh.Bytes()...)
+
+	return crypto.Keccak256Hash(buf), nil
+}
+
+func eip712DomainSeparator(domain EIP712Domain) (common.Hash, error) {
+	typBytes32, err := abi.NewType("bytes32", "", nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	typUint256, err := abi.NewType("uint256", "", nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	typAddress, err := abi.NewType("address", "", nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	args := abi.Arguments([]abi.Argument{
+		{Type: typBytes32},
+		{Type: typBytes32},
+		{Type: typBytes32},
+		{Type: typUint256},
+		{Type: typAddress},
+	})
+
+	packed, err := args.Pack(
+		[32]byte(eip712DomainTypeHash),
+		[32]byte(eip712Name),
+		[32]byte(eip712DomainVersion),
+		domain.ChainID,
+		domain.VerifyingContract,
+	)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return crypto.Keccak256Hash(packed), nil
+}
+
+func eip712StructHash(fields messageFields) (common.Hash, error) {
+	typBytes32, err := abi.NewType("bytes32", "", nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	typUint64, err := abi.NewType("uint64", "", nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	typUint256, err := abi.NewType("uint256", "", nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	args := abi.Arguments([]abi.Argument{
+		{Type: typBytes32},
+		{Type: typBytes32},
+		{Type: typUint64},
+		{Type: typBytes32},
+		{Type: typUint256},
+	})
+
+	packed, err := args.Pack(
+		[32]byte(eip712MessageTypeHash),
+		[32]byte(crypto.Keccak256Hash([]byte(fields.kind))),
+		fields.timestamp,
+		[32]byte(crypto.Keccak256Hash([]byte(fields.key))),
+		fields.value,
+	)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return crypto.Keccak256Hash(packed), nil
+}
+
+// verifyEIP712 verifies a payload signed with ModeEIP712 against domain.
+func verifyEIP712(oresp OracleResponse, domain EIP712Domain) ([]string, map[string]string, error) {
+	if len(oresp.Messages) != len(oresp.Signatures) {
+		return nil, nil, fmt.Errorf("got %v signatures, but have %v messages", len(oresp.Signatures), len(oresp.Messages))
+	}
+
+	typString, err := abi.NewType("string", "", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	typUint64, err := abi.NewType("uint64", "", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	typUint256, err := abi.NewType("uint256", "", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args := abi.Arguments([]abi.Argument{
+		{Name: "kind", Type: typString},
+		{Name: "timestamp", Type: typUint64},
+		{Name: "key", Type: typString},
+		{Name: "value", Type: typUint256},
+	})
+
+	pubkeys := map[string]struct{}{}
+	keyValues := map[string]string{}
+
+	for i := 0; i < len(oresp.Messages); i++ {
+		msgBytes, err := hex.DecodeString(strings.TrimPrefix(oresp.Messages[i], "0x"))
+		if err != nil {
+			return nil, nil, err
+		}
+		sigBytes, err := hex.DecodeString(strings.TrimPrefix(oresp.Signatures[i], "0x"))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		m := map[string]interface{}{}
+		if err := args.UnpackIntoMap(m, msgBytes); err != nil {
+			return nil, nil, err
+		}
+
+		fields := messageFields{
+			kind:      m["kind"].(string),
+			key:       m["key"].(string),
+			timestamp: m["timestamp"].(uint64),
+			value:     m["value"].(*big.Int),
+		}
+
+		digest, err := eip712Digest(fields, domain)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(sigBytes) > 65 {
+			sigBytes[64] = sigBytes[len(sigBytes)-1]
+			sigBytes = sigBytes[:65]
+		}
+		sigBytes[64] = sigBytes[64] - 27
+
+		sigPublicKeyECDSA, err := crypto.SigToPub(digest.Bytes(), sigBytes)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		addrHex := crypto.PubkeyToAddress(*sigPublicKeyECDSA).Hex()
+		pubkeys[addrHex] = struct{}{}
+
+		keyValues[fmt.Sprintf("%v.%v.value", fields.kind, fields.key)] = fmt.Sprintf("%v", fields.value)
+		keyValues[fmt.Sprintf("%v.%v.timestamp", fields.kind, fields.key)] = fmt.Sprintf("%v", fields.timestamp)
+	}
+
+	pubkeysSlice := make([]string, 0, len(pubkeys))
+	for k := range pubkeys {
+		pubkeysSlice = append(pubkeysSlice, k)
+	}
+
+	return pubkeysSlice, keyValues, nil
+}
@@ -0,0 +1,135 @@
+package openoracle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func testDomain() EIP712Domain {
+	return EIP712Domain{
+		ChainID:           big.NewInt(1),
+		VerifyingContract: common.HexToAddress("0x1111111111111111111111111111111111111111"),
+	}
+}
+
+func TestSignEIP712VerifiesAgainstSameDomain(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	domain := testDomain()
+	fields := messageFields{kind: "prices", key: "BTC", timestamp: 42, value: big.NewInt(100)}
+
+	sig, err := signEIP712(fields, domain, privKey)
+	if err != nil {
+		t.Fatalf("signEIP712() error = %v", err)
+	}
+
+	digest, err := eip712Digest(fields, domain)
+	if err != nil {
+		t.Fatalf("eip712Digest() error = %v", err)
+	}
+
+	sig[64] = sig[64] - 27
+	recovered, err := crypto.SigToPub(digest.Bytes(), sig)
+	if err != nil {
+		t.Fatalf("SigToPub() error = %v", err)
+	}
+
+	want := crypto.PubkeyToAddress(privKey.PublicKey)
+	got := crypto.PubkeyToAddress(*recovered)
+	if got != want {
+		t.Fatalf("recovered address = %v, want %v", got, want)
+	}
+}
+
+func TestEIP712DigestDiffersAcrossDomains(t *testing.T) {
+	fields := messageFields{kind: "prices", key: "BTC", timestamp: 42, value: big.NewInt(100)}
+
+	domainA := testDomain()
+	domainB := testDomain()
+	domainB.ChainID = big.NewInt(2)
+
+	digestA, err := eip712Digest(fields, domainA)
+	if err != nil {
+		t.Fatalf("eip712Digest() error = %v", err)
+	}
+	digestB, err := eip712Digest(fields, domainB)
+	if err != nil {
+		t.Fatalf("eip712Digest() error = %v", err)
+	}
+
+	if digestA == digestB {
+		t.Fatalf("eip712Digest() produced the same digest for different chain IDs: %v", digestA)
+	}
+}
+
+func TestEIP712DigestDiffersAcrossFields(t *testing.T) {
+	domain := testDomain()
+
+	digestA, err := eip712Digest(messageFields{kind: "prices", key: "BTC", timestamp: 42, value: big.NewInt(100)}, domain)
+	if err != nil {
+		t.Fatalf("eip712Digest() error = %v", err)
+	}
+	digestB, err := eip712Digest(messageFields{kind: "prices", key: "BTC", timestamp: 42, value: big.NewInt(101)}, domain)
+	if err != nil {
+		t.Fatalf("eip712Digest() error = %v", err)
+	}
+
+	if digestA == digestB {
+		t.Fatalf("eip712Digest() produced the same digest for different prices: %v", digestA)
+	}
+}
+
+func TestIntoOpenOracleEIP712RoundTrip(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	domain := testDomain()
+
+	oreq := OracleRequest{
+		Timestamp: 42,
+		Prices: []OraclePrice{
+			{Asset: "BTC", Price: "100", Timestamp: 42},
+		},
+	}
+
+	oresp, err := oreq.IntoOpenOracle(privKey, ModeEIP712, &domain)
+	if err != nil {
+		t.Fatalf("IntoOpenOracle() error = %v", err)
+	}
+	if oresp.Version != eip712Version {
+		t.Fatalf("oresp.Version = %q, want %q", oresp.Version, eip712Version)
+	}
+
+	addrs, keyValues, err := Verify(*oresp, domain)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	want := crypto.PubkeyToAddress(privKey.PublicKey).Hex()
+	if len(addrs) != 1 || addrs[0] != want {
+		t.Fatalf("Verify() addresses = %v, want [%v]", addrs, want)
+	}
+	if keyValues["prices.BTC.value"] != "100" {
+		t.Fatalf("keyValues[prices.BTC.value] = %q, want \"100\"", keyValues["prices.BTC.value"])
+	}
+
+	// Verifying against the wrong domain recovers a different (wrong)
+	// address rather than erroring outright, since ecrecover always
+	// returns some public key for a well-formed signature; the signer
+	// must not be mistaken for the real one.
+	wrongDomain := testDomain()
+	wrongDomain.ChainID = big.NewInt(999)
+	wrongAddrs, _, err := Verify(*oresp, wrongDomain)
+	if err != nil {
+		t.Fatalf("Verify() with wrong domain error = %v", err)
+	}
+	if len(wrongAddrs) == 1 && wrongAddrs[0] == want {
+		t.Fatalf("Verify() with wrong domain recovered the real signer %v", want)
+	}
+}
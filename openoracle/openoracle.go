@@ -38,6 +38,10 @@ type OracleResponse struct {
 	Signatures []string `json:"signatures"`
 	// An option decoded map of asset -> price
 	Prices map[string]string `json:"prices"`
+	// Version marks the signing mode used to produce this payload.
+	// Payloads signed with ModeEIP712 set this to "eip712"; payloads
+	// signed with ModeEthSignedMessage, the default, omit it.
+	Version string `json:"version,omitempty"`
 }
 
 // An oracle price input
@@ -48,6 +52,14 @@ type OraclePrice struct {
 	Price string
 	// The timestamp at which this specific price was valid
 	Timestamp uint64
+	// The traded volume backing this price, as a string representing an
+	// unsigned integer. Optional, leave empty if the source producing
+	// this price does not report a volume.
+	Volume string
+	// RoundID is an optional, source-specific identifier kept for
+	// traceability (e.g. a Chainlink round id). It is not part of the
+	// signed message built by IntoOpenOracle.
+	RoundID string
 }
 
 // A request to build an open oracle payload
@@ -58,12 +70,22 @@ type OracleRequest struct {
 	Prices []OraclePrice `json:"oracle_prices"`
 }
 
-func (oreq *OracleRequest) IntoOpenOracle(privKey *ecdsa.PrivateKey) (*OracleResponse, error) {
+// IntoOpenOracle signs every price in oreq according to mode, and
+// bundles the result into an OracleResponse. domain is only used, and
+// required, when mode is ModeEIP712.
+func (oreq *OracleRequest) IntoOpenOracle(privKey *ecdsa.PrivateKey, mode SignMode, domain *EIP712Domain) (*OracleResponse, error) {
+	if mode == ModeEIP712 && domain == nil {
+		return nil, fmt.Errorf("an eip712 domain is required to sign with ModeEIP712")
+	}
+
 	oresp := OracleResponse{
 		Timestamp:  fmt.Sprintf("%d", oreq.Timestamp),
 		Messages:   make([]string, 0, len(oreq.Prices)),
 		Signatures: make([]string, 0, len(oreq.Prices)),
 	}
+	if mode == ModeEIP712 {
+		oresp.Version = eip712Version
+	}
 
 	for _, v := range oreq.Prices {
 		price, _ := big.NewInt(0).SetString(v.Price, 10)
@@ -75,7 +97,12 @@ func (oreq *OracleRequest) IntoOpenOracle(privKey *ecdsa.PrivateKey) (*OracleRes
 			return nil, err
 		}
 
-		sigBytes, err := signMessage(msgBytes, privKey)
+		var sigBytes []byte
+		if mode == ModeEIP712 {
+			sigBytes, err = signEIP712(messageFields{kind: "prices", key: v.Asset, timestamp: v.Timestamp, value: price}, *domain, privKey)
+		} else {
+			sigBytes, err = signMessage(msgBytes, privKey)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -151,21 +178,6 @@ func makeMessage(
 	return bytes, err
 }
 
-// UnmarshalVerify will unmarshal a json raw payload
-// into and OracleResponse
-// if the unmarshal is successful then the content is verified
-func UnmarshalVerify(payload []byte, address string) (*OracleResponse, error) {
-	oresp, err := Unmarshal(payload)
-	if err != nil {
-		return nil, err
-	}
-
-	pk, kv, err := Verify(*oresp)
-	fmt.Printf("%v\n%v\n", pk, kv)
-
-	return oresp, err
-}
-
 func Unmarshal(payload []byte) (*OracleResponse, error) {
 	oresp := OracleResponse{}
 	err := json.Unmarshal(payload, &oresp)
@@ -175,7 +187,23 @@ func Unmarshal(payload []byte) (*OracleResponse, error) {
 	return &oresp, nil
 }
 
-func Verify(oresp OracleResponse) ([]string, map[string]string, error) {
+// Verify checks the signatures of oresp, returning the addresses that
+// signed it alongside a decoded map of asset -> value/timestamp. A
+// payload produced with ModeEIP712 (marked by Version == "eip712") is
+// verified against domain, which the caller must supply; every other
+// payload falls back to the original ModeEthSignedMessage
+// verification and ignores domain.
+func Verify(oresp OracleResponse, domain ...EIP712Domain) ([]string, map[string]string, error) {
+	if oresp.Version == eip712Version {
+		if len(domain) == 0 {
+			return nil, nil, fmt.Errorf("payload is signed with eip712, but no domain was provided to verify it")
+		}
+		return verifyEIP712(oresp, domain[0])
+	}
+	return verifyEthSignedMessage(oresp)
+}
+
+func verifyEthSignedMessage(oresp OracleResponse) ([]string, map[string]string, error) {
 	typString, err := abi.NewType("string", "", nil)
 	if err != nil {
 		return nil, nil, err
@@ -262,3 +290,51 @@ func Verify(oresp OracleResponse) ([]string, map[string]string, error) {
 
 	return pubkeysSlice, keyValues, nil
 }
+
+// DecodePrices turns the keyValues map returned by Verify back into a
+// list of OraclePrice, one per distinct asset key it contains.
+func DecodePrices(keyValues map[string]string) ([]OraclePrice, error) {
+	byAsset := map[string]*OraclePrice{}
+	for k, v := range keyValues {
+		kind, asset, field, err := splitKey(k)
+		if err != nil {
+			return nil, err
+		}
+		if kind != "prices" {
+			continue
+		}
+
+		p, ok := byAsset[asset]
+		if !ok {
+			p = &OraclePrice{Asset: asset}
+			byAsset[asset] = p
+		}
+
+		switch field {
+		case "value":
+			p.Price = v
+		case "timestamp":
+			ts, ok := big.NewInt(0).SetString(v, 10)
+			if !ok {
+				return nil, fmt.Errorf("invalid timestamp %q for asset %q", v, asset)
+			}
+			p.Timestamp = ts.Uint64()
+		}
+	}
+
+	prices := make([]OraclePrice, 0, len(byAsset))
+	for _, p := range byAsset {
+		prices = append(prices, *p)
+	}
+	return prices, nil
+}
+
+// splitKey splits the "kind.key.field" keys produced by Verify.
+func splitKey(k string) (kind, asset, field string, err error) {
+	first := strings.Index(k, ".")
+	last := strings.LastIndex(k, ".")
+	if first < 0 || last <= first {
+		return "", "", "", fmt.Errorf("malformed key %q", k)
+	}
+	return k[:first], k[first+1 : last], k[last+1:], nil
+}
@@ -0,0 +1,52 @@
+package sources
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pelletier/go-toml"
+)
+
+// Factory decodes a backend's TOML configuration section and builds
+// the Source it describes, along with the frequency it should be
+// polled at.
+type Factory func(tree *toml.Tree) (Source, time.Duration, error)
+
+// registry maps a top-level TOML section name (e.g. "coinbase") to
+// the factory that builds the backend configured under it.
+var registry = map[string]Factory{}
+
+// Register associates a TOML section name with the factory used to
+// build the Source configured under it. Backends call this from an
+// init function so that Discover can find them; a new backend needs
+// only a blank import in main.go, and no change to config.go.
+func Register(section string, factory Factory) {
+	registry[section] = factory
+}
+
+// Discover builds a Source, and the frequency it should be polled at,
+// for every registered backend whose section is present in tree.
+// Sections with no matching registration are ignored, so unrelated
+// TOML tables (aggregator, vega, gossip, ...) can live alongside
+// source sections in the same config file.
+func Discover(tree *toml.Tree) (map[string]Source, map[string]time.Duration, error) {
+	srcs := make(map[string]Source, len(registry))
+	freqs := make(map[string]time.Duration, len(registry))
+
+	for section, factory := range registry {
+		sub, ok := tree.Get(section).(*toml.Tree)
+		if !ok {
+			continue
+		}
+
+		src, freq, err := factory(sub)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", section, err)
+		}
+
+		srcs[section] = src
+		freqs[section] = freq
+	}
+
+	return srcs, freqs, nil
+}
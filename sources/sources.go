@@ -0,0 +1,85 @@
+// Package sources defines the common interface implemented by every
+// price backend the relay can pull from (Coinbase and an on-chain
+// Chainlink aggregator today, with Binance, Kraken and a Uniswap v3
+// TWAP expected to follow). Each backend is responsible for fetching
+// its own data and decoding it into OraclePrice values; it registers
+// itself under its TOML section name via Register, so main discovers
+// and runs whichever backends are present in the config file, needing
+// only a blank import for every new one. The package also provides the
+// generic driver used to run every backend uniformly, each at its own
+// frequency and with its own backoff.
+package sources
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"code.vegaprotocol.io/oracles-relay/metrics"
+	"code.vegaprotocol.io/oracles-relay/openoracle"
+)
+
+// Source is implemented by every price backend the relay can pull
+// from.
+type Source interface {
+	// Name identifies this source. It is used for logging and to
+	// attribute a price to a distinct, independent source when
+	// aggregating across backends.
+	Name() string
+	// PubKey returns the address expected to have signed this
+	// source's payloads.
+	PubKey() string
+	// Pull fetches the latest prices from this source.
+	Pull(ctx context.Context) ([]openoracle.OraclePrice, error)
+}
+
+// PriceUpdate is a single price reported by a source, sent over the
+// channel passed to Run.
+type PriceUpdate struct {
+	Source string
+	Price  openoracle.OraclePrice
+}
+
+// maxBackoff caps the exponential backoff applied to a source after
+// repeated Pull failures.
+const maxBackoff = 5 * time.Minute
+
+// Run polls src every freq, sending every price it reports on ch,
+// until ctx is cancelled. A Pull error doubles the wait before the
+// next attempt, up to maxBackoff; a successful Pull resets it back to
+// freq.
+func Run(ctx context.Context, src Source, freq time.Duration, ch chan<- PriceUpdate, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	backoff := freq
+	t := time.NewTimer(freq)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			start := time.Now()
+			prices, err := src.Pull(ctx)
+			metrics.PullLatency.WithLabelValues(src.Name()).Observe(time.Since(start).Seconds())
+			if err != nil {
+				log.Printf("error pulling data from %v: %v", src.Name(), err)
+				backoff *= 2
+				if backoff > maxBackoff {
+					backoff = maxBackoff
+				}
+				t.Reset(backoff)
+				continue
+			}
+
+			for _, p := range prices {
+				ch <- PriceUpdate{Source: src.Name(), Price: p}
+			}
+
+			backoff = freq
+			t.Reset(freq)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Package store persists verified prices to disk, so that the history
+// of what this relay has seen and signed can be replayed later: to
+// independently re-verify a payload with openoracle.Verify, to compute
+// a TWAP over an arbitrary window, or to alert on a source that has
+// gone quiet.
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Record is a single verified price, persisted alongside the raw
+// message and signature it was extracted from.
+type Record struct {
+	Asset     string `json:"asset"`
+	Source    string `json:"source"`
+	Price     string `json:"price"`
+	Timestamp uint64 `json:"timestamp"`
+	// Message and Signature are the ABI encoded message and signature
+	// this price was decoded from, kept so the record can be replayed
+	// through openoracle.Verify independently of this relay.
+	Message   string `json:"message"`
+	Signature string `json:"signature"`
+}
+
+// Config is the persistent store configuration.
+type Config struct {
+	// Path is where the store's database file is kept on disk.
+	Path string `toml:"path"`
+}
+
+// Store persists Records to a BoltDB file, bucketed by asset and
+// keyed by timestamp.
+type Store struct {
+	db *bolt.DB
+}
+
+// New opens, creating if necessary, the BoltDB file at cfg.Path.
+func New(cfg Config) (*Store, error) {
+	db, err := bolt.Open(cfg.Path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open store at %v: %w", cfg.Path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying database file.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Put persists rec, bucketed by rec.Asset and keyed by rec.Timestamp.
+// A later Put for the same (asset, timestamp) pair overwrites the
+// previous record.
+func (s *Store) Put(rec Record) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(rec.Asset))
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+
+		return b.Put(timestampKey(rec.Timestamp), data)
+	})
+}
+
+// Range returns every record stored for asset with a timestamp in
+// [from, to], ordered oldest first.
+func (s *Store) Range(asset string, from, to uint64) ([]Record, error) {
+	var records []Record
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(asset))
+		if b == nil {
+			return nil
+		}
+
+		c := b.Cursor()
+		for k, v := c.Seek(timestampKey(from)); k != nil && binary.BigEndian.Uint64(k) <= to; k, v = c.Next() {
+			var rec Record
+			if err := json.Unmarshal(v, &rec); err != nil {
+				return err
+			}
+			records = append(records, rec)
+		}
+		return nil
+	})
+
+	return records, err
+}
+
+// Latest returns the record with the highest timestamp stored for
+// asset, or nil if nothing has been stored for it yet.
+func (s *Store) Latest(asset string) (*Record, error) {
+	var rec *Record
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(asset))
+		if b == nil {
+			return nil
+		}
+
+		k, v := b.Cursor().Last()
+		if k == nil {
+			return nil
+		}
+
+		rec = &Record{}
+		return json.Unmarshal(v, rec)
+	})
+
+	return rec, err
+}
+
+func timestampKey(ts uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, ts)
+	return key
+}
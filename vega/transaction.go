@@ -0,0 +1,71 @@
+package vega
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	apipb "code.vegaprotocol.io/protos/vega/api/v1"
+	commandspb "code.vegaprotocol.io/protos/vega/commands/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// buildTransaction wraps payload as a signed oracle data submission,
+// ready to be sent to vega with SubmitTransaction. The current block
+// height is fetched from the node on every call, since a transaction
+// built against a stale height is rejected by the mempool.
+func (p *Pipeline) buildTransaction(ctx context.Context, payload []byte) (*commandspb.Transaction, error) {
+	height, err := p.lastBlockHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch last block height: %w", err)
+	}
+
+	rawNonce, err := strconv.ParseUint(nonce(), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse nonce: %w", err)
+	}
+
+	inputData := &commandspb.InputData{
+		Nonce:       rawNonce,
+		BlockHeight: height,
+		Command: &commandspb.InputData_OracleDataSubmission{
+			OracleDataSubmission: &commandspb.OracleDataSubmission{
+				Source:  commandspb.OracleDataSubmission_ORACLE_SOURCE_JSON,
+				Payload: payload,
+			},
+		},
+	}
+
+	inputDataBytes, err := proto.Marshal(inputData)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal input data: %w", err)
+	}
+
+	sig := ed25519.Sign(p.key, inputDataBytes)
+	pubKey := p.key.Public().(ed25519.PublicKey)
+
+	return &commandspb.Transaction{
+		InputData: inputDataBytes,
+		Signature: &commandspb.Signature{
+			Value:   hex.EncodeToString(sig),
+			Algo:    "vega/ed25519",
+			Version: 1,
+		},
+		From: &commandspb.Transaction_PubKey{
+			PubKey: hex.EncodeToString(pubKey),
+		},
+		Version: 3,
+	}, nil
+}
+
+// lastBlockHeight asks the vega node for the height of the last block
+// it processed.
+func (p *Pipeline) lastBlockHeight(ctx context.Context) (uint64, error) {
+	resp, err := p.client.LastBlockHeight(ctx, &apipb.LastBlockHeightRequest{})
+	if err != nil {
+		return 0, err
+	}
+	return resp.Height, nil
+}
@@ -0,0 +1,182 @@
+// Package vega submits verified Open Oracle payloads to a Vega node
+// as oracle data submission transactions over gRPC. It is the last
+// step of the pipeline: sources feed the aggregator, the aggregator
+// emits a single consensus price signed by the relay's key, and this
+// package gets that signed payload onto the chain.
+package vega
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"code.vegaprotocol.io/oracles-relay/crypto"
+	"code.vegaprotocol.io/oracles-relay/metrics"
+	"code.vegaprotocol.io/oracles-relay/openoracle"
+	apipb "code.vegaprotocol.io/protos/vega/api/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Config is the vega submission pipeline configuration.
+type Config struct {
+	// WalletKey is the hex encoded ed25519 private key used to sign
+	// oracle data submission transactions sent to vega.
+	WalletKey string `toml:"wallet_key"`
+	// MaxRetries is how many times a submission rejected by the
+	// mempool is retried before being dropped.
+	MaxRetries int `toml:"max_retries"`
+	// QueueSize bounds how many signed payloads can be buffered
+	// waiting for submission before new ones are dropped, so that a
+	// slow node can't starve the workers feeding the relay.
+	QueueSize int `toml:"queue_size"`
+}
+
+type submission struct {
+	asset string
+	price openoracle.OraclePrice
+	oresp *openoracle.OracleResponse
+}
+
+type lastSubmission struct {
+	price     string
+	timestamp uint64
+}
+
+// Pipeline buffers signed Open Oracle payloads and submits them to a
+// vega node from a dedicated goroutine, deduplicating resubmissions of
+// the same price and retrying on mempool rejection.
+type Pipeline struct {
+	cfg      Config
+	nodeAddr string
+	key      ed25519.PrivateKey
+	conn     *grpc.ClientConn
+	client   apipb.CoreServiceClient
+	queue    chan submission
+
+	mu      sync.Mutex
+	lastSub map[string]lastSubmission // asset -> last submitted price/timestamp
+}
+
+// New dials nodeAddr and returns a Pipeline ready to be run.
+func New(nodeAddr string, cfg Config) (*Pipeline, error) {
+	seed, err := hex.DecodeString(cfg.WalletKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wallet_key: %w", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("wallet_key must be a %d byte hex encoded ed25519 seed", ed25519.SeedSize)
+	}
+
+	conn, err := grpc.Dial(nodeAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("unable to dial vega node %v: %w", nodeAddr, err)
+	}
+
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = 1000
+	}
+
+	return &Pipeline{
+		cfg:      cfg,
+		nodeAddr: nodeAddr,
+		key:      ed25519.NewKeyFromSeed(seed),
+		conn:     conn,
+		client:   apipb.NewCoreServiceClient(conn),
+		queue:    make(chan submission, queueSize),
+		lastSub:  map[string]lastSubmission{},
+	}, nil
+}
+
+// Enqueue queues oresp for submission to vega, attributed to asset and
+// price for deduplication. If the queue is full the submission is
+// dropped rather than blocking the caller -- a slow vega node should
+// never starve the workers feeding prices into the relay.
+func (p *Pipeline) Enqueue(asset string, price openoracle.OraclePrice, oresp *openoracle.OracleResponse) {
+	select {
+	case p.queue <- submission{asset: asset, price: price, oresp: oresp}:
+	default:
+		metrics.VegaSubmissions.WithLabelValues(asset, "dropped").Inc()
+		log.Printf("vega submission queue is full, dropping update for %v", asset)
+	}
+}
+
+// Run drains the submission queue, submitting every entry to vega
+// until ctx is cancelled.
+func (p *Pipeline) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case s := <-p.queue:
+			if err := p.submit(ctx, s); err != nil {
+				log.Printf("error submitting oracle data for %v: %v", s.asset, err)
+			}
+		}
+	}
+}
+
+// submit signs s as an oracle data submission transaction and sends it
+// to vega, retrying on mempool rejection up to MaxRetries times.
+// Submissions for an asset already sent at the same price and
+// timestamp are skipped.
+func (p *Pipeline) submit(ctx context.Context, s submission) error {
+	if p.isDuplicate(s) {
+		metrics.VegaSubmissions.WithLabelValues(s.asset, "duplicate").Inc()
+		return nil
+	}
+
+	payload, err := json.Marshal(s.oresp)
+	if err != nil {
+		return fmt.Errorf("unable to marshal oracle response: %w", err)
+	}
+
+	var submitErr error
+	for attempt := 0; attempt <= p.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			metrics.VegaSubmissions.WithLabelValues(s.asset, "retried").Inc()
+		}
+
+		tx, err := p.buildTransaction(ctx, payload)
+		if err != nil {
+			return fmt.Errorf("unable to build transaction: %w", err)
+		}
+
+		_, submitErr = p.client.SubmitTransaction(ctx, &apipb.SubmitTransactionRequest{
+			Tx:   tx,
+			Type: apipb.SubmitTransactionRequest_TYPE_ASYNC,
+		})
+		if submitErr == nil {
+			p.markSubmitted(s)
+			metrics.VegaSubmissions.WithLabelValues(s.asset, "submitted").Inc()
+			return nil
+		}
+	}
+
+	metrics.VegaSubmissions.WithLabelValues(s.asset, "rejected").Inc()
+	return fmt.Errorf("submission rejected after %d attempts: %w", p.cfg.MaxRetries+1, submitErr)
+}
+
+func (p *Pipeline) isDuplicate(s submission) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	last, ok := p.lastSub[s.asset]
+	return ok && last.price == s.price.Price && last.timestamp == s.price.Timestamp
+}
+
+func (p *Pipeline) markSubmitted(s submission) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastSub[s.asset] = lastSubmission{price: s.price.Price, timestamp: s.price.Timestamp}
+}
+
+// nonce is a thin wrapper so the vega submission signs with the same
+// nonce helper used everywhere else in this codebase.
+func nonce() string {
+	return crypto.MakeNonce()
+}